@@ -0,0 +1,206 @@
+/*
+Copyright (C) 2018 Expedia Group.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package client is the pack author's view of flyte-api: it takes actions, posts back results and
+// events, and lets packs discover flyte-api's other endpoints without hardcoding URLs.
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+const healthCheckRel = "info/health"
+const takeActionRel = "pack/takeAction"
+
+// Client is the pack author's entry point into flyte-api.
+type Client interface {
+	// GetFlyteHealthCheckURL returns the URL of flyte-api's health check endpoint.
+	GetFlyteHealthCheckURL() (*url.URL, error)
+	// TakeAction long-polls flyte-api for the next action the pack should execute. It is
+	// equivalent to TakeActionContext(context.Background()).
+	TakeAction() (*Action, error)
+	// TakeActionContext is TakeAction with a context, so a pack can abort the long poll - e.g. on
+	// shutdown - instead of waiting for it to complete.
+	TakeActionContext(ctx context.Context) (*Action, error)
+	// Request returns a fluent builder for calling any endpoint flyte-api advertises via a HAL
+	// link, for packs that need more than GetFlyteHealthCheckURL/TakeAction expose.
+	Request() *Request
+}
+
+// client is flyte-api's Client implementation. takeActionURL is resolved once, from the links
+// flyte-api advertises, rather than being rebuilt on every call. links is re-read under linksMu
+// since Request can refresh it after construction.
+type client struct {
+	baseURL       *url.URL
+	httpClient    *http.Client
+	links         []link
+	linksMu       sync.Mutex
+	takeActionURL *url.URL
+	retryPolicy   *RetryPolicy
+	logger        Logger
+	metrics       *metrics
+}
+
+// Option configures a client created with NewClientWithOptions.
+type Option func(*client)
+
+// WithRetryPolicy overrides the retry policy applied to every call the client makes. The default,
+// used when this option is omitted, is DefaultRetryPolicy().
+func WithRetryPolicy(policy *RetryPolicy) Option {
+	return func(c *client) { c.retryPolicy = policy }
+}
+
+// NewClient creates a Client that talks to the flyte-api found at baseURL, using timeout as the
+// per-request HTTP timeout. It is equivalent to NewClientWithOptions(baseURL, timeout) with no
+// options, i.e. the default retry policy.
+func NewClient(baseURL *url.URL, timeout time.Duration) Client {
+	return NewClientWithOptions(baseURL, timeout)
+}
+
+// NewClientWithOptions creates a Client as NewClient does, additionally applying opts - e.g.
+// WithRetryPolicy to change how transient failures are retried.
+func NewClientWithOptions(baseURL *url.URL, timeout time.Duration, opts ...Option) Client {
+	c := &client{baseURL: baseURL}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.retryPolicy == nil {
+		c.retryPolicy = DefaultRetryPolicy()
+	}
+	if c.logger == nil {
+		c.logger = goLogger{}
+	}
+	c.httpClient = &http.Client{
+		Timeout: timeout,
+		Transport: &retryTransport{
+			next:    http.DefaultTransport,
+			policy:  c.retryPolicy,
+			logger:  c.logger,
+			metrics: c.metrics,
+		},
+	}
+
+	links, err := c.getFlyteApiLinks()
+	if err != nil {
+		c.logger.Error("cannot get api links", "url", baseURL.String(), "err", err)
+		// retryTransport's RetryPolicy already retries connection/5xx/429 failures, so only retry
+		// here for a malformed body - a 200 response RetryPolicy has no reason to consider a failure.
+		var malformed malformedLinksError
+		if errors.As(err, &malformed) {
+			links, err = c.getFlyteApiLinks()
+			if err != nil {
+				c.logger.Error("cannot get api links", "url", baseURL.String(), "err", err)
+			}
+		}
+	}
+	c.links = links
+
+	if href, err := findHref(takeActionRel, c.links); err == nil {
+		if u, err := url.Parse(href); err == nil {
+			c.takeActionURL = u
+		}
+	}
+
+	return c
+}
+
+// getFlyteApiLinksRel identifies, for metrics, calls that refresh the root HAL links.
+const getFlyteApiLinksRel = "self"
+
+// getFlyteApiLinks fetches and decodes the HAL links flyte-api advertises at its root.
+func (c *client) getFlyteApiLinks() ([]link, error) {
+	req, err := http.NewRequest(http.MethodGet, c.baseURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.httpClient.Do(req.WithContext(withRel(context.Background(), getFlyteApiLinksRel)))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var body halResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, malformedLinksError{err: err}
+	}
+	return body.Links, nil
+}
+
+// malformedLinksError wraps a failure to decode flyte-api's root response - a 200 with a body
+// retryTransport's RetryPolicy has no reason to have retried, unlike a connection failure or a
+// 5xx/429 it already exhausted its own budget against.
+type malformedLinksError struct {
+	err error
+}
+
+func (e malformedLinksError) Error() string { return "malformed flyte-api links response: " + e.err.Error() }
+
+func (e malformedLinksError) Unwrap() error { return e.err }
+
+func (c *client) GetFlyteHealthCheckURL() (*url.URL, error) {
+	c.linksMu.Lock()
+	links := c.links
+	c.linksMu.Unlock()
+
+	href, err := findHref(healthCheckRel, links)
+	if err != nil {
+		return nil, err
+	}
+	return url.Parse(href)
+}
+
+// TakeAction long-polls flyte-api for the next action, returning NotFoundError if there is none
+// currently available. It is equivalent to TakeActionContext(context.Background()).
+func (c *client) TakeAction() (*Action, error) {
+	return c.TakeActionContext(context.Background())
+}
+
+// TakeActionContext is TakeAction with a context: ctx is threaded into the underlying
+// http.Request, and the retry loop backing the call checks ctx.Done() between attempts so a
+// cancelled or expired context aborts the long poll promptly.
+func (c *client) TakeActionContext(ctx context.Context) (*Action, error) {
+	req, err := http.NewRequest(http.MethodGet, c.takeActionURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.httpClient.Do(req.WithContext(withRel(ctx, takeActionRel)))
+	if err != nil {
+		return nil, unwrapUnexpectedStatus(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, NotFoundError{url: c.takeActionURL.String()}
+	}
+
+	var action Action
+	if err := json.NewDecoder(resp.Body).Decode(&action); err != nil {
+		return nil, err
+	}
+	return &action, nil
+}
+
+// Action is a unit of work flyte-api hands to a pack via TakeAction.
+type Action struct {
+	Name  string      `json:"name"`
+	Input interface{} `json:"input,omitempty"`
+}
@@ -17,18 +17,45 @@ limitations under the License.
 package client
 
 import (
+	"bytes"
 	"fmt"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"testing"
 	"time"
-	"bytes"
-	"github.com/stretchr/testify/require"
-	"github.com/HotelsDotCom/go-logger"
 )
 
+// capturingLogger is a Logger that records what it was told, so tests can assert on structured
+// fields instead of mutating the package-level go-logger.
+type capturingLogger struct {
+	errors []loggedCall
+}
+
+type loggedCall struct {
+	msg string
+	kv  []interface{}
+}
+
+func (l *capturingLogger) Debug(msg string, kv ...interface{}) {}
+func (l *capturingLogger) Info(msg string, kv ...interface{})  {}
+func (l *capturingLogger) Warn(msg string, kv ...interface{})  {}
+func (l *capturingLogger) Error(msg string, kv ...interface{}) {
+	l.errors = append(l.errors, loggedCall{msg: msg, kv: kv})
+}
+
+// field returns the value following key in kv, or nil if key isn't present.
+func field(kv []interface{}, key string) interface{} {
+	for i := 0; i+1 < len(kv); i += 2 {
+		if kv[i] == key {
+			return kv[i+1]
+		}
+	}
+	return nil
+}
+
 func Test_NewClient_ShouldRetryOnErrorGettingFlyteApiLinks(t *testing.T) {
 	// given the mock flyte-api will first return an error response getting api links...then after retrying will return the expected response
 	apiLinksFailCount := 1
@@ -43,19 +70,19 @@ func Test_NewClient_ShouldRetryOnErrorGettingFlyteApiLinks(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(handler))
 	defer server.Close()
 
-	// and code to record the log message/s
-	logMsg := ""
-	loggerFn := logger.Errorf
-	logger.Errorf = func(msg string, args ...interface{}) { logMsg = fmt.Sprintf(msg, args...) }
-	defer func() { logger.Errorf = loggerFn }()
+	// and a logger to capture the error instead of the package-level go-logger
+	capturedLogger := &capturingLogger{}
 
 	baseUrl, _ := url.Parse(server.URL)
 
 	// when
-	client := NewClient(baseUrl, 10 * time.Second)
+	client := NewClientWithOptions(baseUrl, 10*time.Second, WithLogger(capturedLogger))
 
-	// then a log error message will have been recorded...
-	assert.Contains(t, logMsg, "cannot get api links:")
+	// then a structured log error will have been recorded...
+	require.Len(t, capturedLogger.errors, 1)
+	assert.Equal(t, "cannot get api links", capturedLogger.errors[0].msg)
+	assert.Equal(t, baseUrl.String(), field(capturedLogger.errors[0].kv, "url"))
+	assert.Error(t, field(capturedLogger.errors[0].kv, "err").(error))
 	// ...but the links are available after the retry
 	healthCheckURL, _ := client.GetFlyteHealthCheckURL()
 	assert.Equal(t, "http://example.com/v1/health", healthCheckURL.String())
@@ -0,0 +1,61 @@
+/*
+Copyright (C) 2018 Expedia Group.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_TakeActionContext_ShouldReturnPromptlyWhenContextIsCancelledMidRequest(t *testing.T) {
+	// given a flyte-api that never responds within the test's lifetime
+	block := make(chan struct{})
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		<-block
+	}
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	// server.Close() blocks until in-flight handlers return, so block must be closed first (defers
+	// run LIFO).
+	defer server.Close()
+	defer close(block)
+
+	u, _ := url.Parse(server.URL)
+	c := &client{takeActionURL: u, httpClient: &http.Client{
+		Transport: &retryTransport{next: http.DefaultTransport, policy: fastRetryPolicy(), logger: goLogger{}},
+	}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	// when
+	start := time.Now()
+	_, err := c.TakeActionContext(ctx)
+	elapsed := time.Since(start)
+
+	// then it returns well within the long poll's normal duration, with a wrapped context.Canceled
+	assert.True(t, elapsed < time.Second, "expected TakeActionContext to return promptly, took %s", elapsed)
+	if assert.Error(t, err) {
+		assert.True(t, errors.Is(err, context.Canceled), "expected error to wrap context.Canceled, got: %v", err)
+	}
+}
@@ -0,0 +1,56 @@
+/*
+Copyright (C) 2018 Expedia Group.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"errors"
+	"fmt"
+)
+
+// NotFoundError is returned when flyte-api responds with a 404 for a resource the client asked for.
+// Pack authors can type-assert on this to distinguish "nothing to do" from a real failure.
+type NotFoundError struct {
+	url string
+}
+
+func (e NotFoundError) Error() string {
+	return fmt.Sprintf("Resource not found at %s", e.url)
+}
+
+// UnexpectedStatusError is returned when a call's retry budget is exhausted against a response
+// that kept looking retryable (5xx, or 429) rather than a call succeeding or failing with a
+// terminal status such as 404.
+type UnexpectedStatusError struct {
+	url    string
+	status string
+}
+
+func (e UnexpectedStatusError) Error() string {
+	return fmt.Sprintf("unexpected response status %q from %s", e.status, e.url)
+}
+
+// unwrapUnexpectedStatus returns the UnexpectedStatusError wrapped inside err, if any. http.Client
+// always wraps a RoundTripper's error in a *url.Error, which would otherwise defeat a pack author's
+// type assertion against UnexpectedStatusError - the same direct-type-assertion pattern NotFoundError
+// supports. err is returned unchanged if it doesn't wrap an UnexpectedStatusError.
+func unwrapUnexpectedStatus(err error) error {
+	var use UnexpectedStatusError
+	if errors.As(err, &use) {
+		return use
+	}
+	return err
+}
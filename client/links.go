@@ -0,0 +1,53 @@
+/*
+Copyright (C) 2018 Expedia Group.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"fmt"
+	"strings"
+)
+
+// link is a single HAL link as returned by flyte-api.
+type link struct {
+	Href string `json:"href"`
+	Rel  string `json:"rel"`
+}
+
+// halResponse is the shape of the flyte-api root document: a list of HAL links pack authors
+// follow instead of hardcoding URLs.
+type halResponse struct {
+	Links []link `json:"links"`
+}
+
+// shortRel returns the part of a flyte-api rel after "#!/", e.g. "http://host/swagger#!/info/health"
+// becomes "info/health". flyte-api rels that don't carry a fragment are returned unchanged.
+func shortRel(rel string) string {
+	if i := strings.Index(rel, "#!/"); i != -1 {
+		return rel[i+len("#!/"):]
+	}
+	return rel
+}
+
+// findHref returns the href of the first link whose shortRel matches rel.
+func findHref(rel string, links []link) (string, error) {
+	for _, l := range links {
+		if shortRel(l.Rel) == rel {
+			return l.Href, nil
+		}
+	}
+	return "", fmt.Errorf("could not find link with rel %q in %v", rel, links)
+}
@@ -0,0 +1,63 @@
+/*
+Copyright (C) 2018 Expedia Group.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/HotelsDotCom/go-logger"
+)
+
+// Logger is how the client reports what it's doing - retries, failures to reach flyte-api, etc.
+// Each method takes a message plus alternating key/value pairs, e.g.
+// logger.Error("cannot get api links", "url", u, "err", err). Use WithLogger to inject one; the
+// default adapts the package-level github.com/HotelsDotCom/go-logger.
+type Logger interface {
+	Debug(msg string, kv ...interface{})
+	Info(msg string, kv ...interface{})
+	Warn(msg string, kv ...interface{})
+	Error(msg string, kv ...interface{})
+}
+
+// WithLogger overrides the Logger the client reports to. The default, used when this option is
+// omitted, adapts github.com/HotelsDotCom/go-logger.
+func WithLogger(l Logger) Option {
+	return func(c *client) { c.logger = l }
+}
+
+// goLogger adapts the package-level github.com/HotelsDotCom/go-logger to Logger, for back-compat
+// with clients that don't supply their own.
+type goLogger struct{}
+
+// go-logger has no Warnf, so Warn is routed through Infof - the closest level it supports.
+func (goLogger) Debug(msg string, kv ...interface{}) { logger.Debugf("%s", withKV(msg, kv)) }
+func (goLogger) Info(msg string, kv ...interface{})  { logger.Infof("%s", withKV(msg, kv)) }
+func (goLogger) Warn(msg string, kv ...interface{})  { logger.Infof("%s", withKV(msg, kv)) }
+func (goLogger) Error(msg string, kv ...interface{}) { logger.Errorf("%s", withKV(msg, kv)) }
+
+// withKV renders msg followed by its key/value pairs as "key=value", e.g.
+// withKV("cannot get api links", []interface{}{"url", u, "err", err}) ->
+// `cannot get api links url=... err=...`. With an odd number of kv, the trailing key is omitted.
+func withKV(msg string, kv []interface{}) string {
+	var b strings.Builder
+	b.WriteString(msg)
+	for i := 0; i+1 < len(kv); i += 2 {
+		fmt.Fprintf(&b, " %v=%v", kv[i], kv[i+1])
+	}
+	return b.String()
+}
@@ -0,0 +1,102 @@
+/*
+Copyright (C) 2018 Expedia Group.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const metricsNamespace = "flyte_client"
+
+// metrics holds the Prometheus collectors registered by WithMetrics. It lives in the same
+// http.RoundTripper layer as retry/logging so it sees every call the client makes - TakeAction,
+// API link refreshes and Request() calls alike - attributed to the rel that initiated the call.
+type metrics struct {
+	duration *prometheus.HistogramVec
+	requests *prometheus.CounterVec
+	retries  *prometheus.CounterVec
+	inflight prometheus.Gauge
+}
+
+// WithMetrics registers Prometheus collectors on reg and instruments every call the client makes:
+// flyte_client_request_duration_seconds (histogram, by rel/method/status_class),
+// flyte_client_requests_total (counter, by rel/method/status_class),
+// flyte_client_retries_total (counter, by rel/reason) and flyte_client_inflight_requests (gauge).
+// A call ended by its cancelled or expired context is counted only by the in-flight gauge while it
+// runs; it is not recorded in the duration histogram or the requests counter.
+func WithMetrics(reg prometheus.Registerer) Option {
+	return func(c *client) { c.metrics = newMetrics(reg) }
+}
+
+func newMetrics(reg prometheus.Registerer) *metrics {
+	m := &metrics{
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "request_duration_seconds",
+			Help:      "Duration in seconds of outbound flyte-api calls, by rel, method and status class.",
+		}, []string{"rel", "method", "status_class"}),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "requests_total",
+			Help:      "Total outbound flyte-api calls, by rel, method and status class.",
+		}, []string{"rel", "method", "status_class"}),
+		retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "retries_total",
+			Help:      "Total retries of outbound flyte-api calls, by rel and reason.",
+		}, []string{"rel", "reason"}),
+		inflight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "inflight_requests",
+			Help:      "Number of outbound flyte-api calls currently in flight.",
+		}),
+	}
+	reg.MustRegister(m.duration, m.requests, m.retries, m.inflight)
+	return m
+}
+
+// observe records the outcome of a finished (no further retries) call.
+func (m *metrics) observe(rel, method string, resp *http.Response, err error, d time.Duration) {
+	class := statusClass(resp, err)
+	m.duration.WithLabelValues(rel, method, class).Observe(d.Seconds())
+	m.requests.WithLabelValues(rel, method, class).Inc()
+}
+
+// recordRetry records that a call for rel is being retried because of reason.
+func (m *metrics) recordRetry(rel, reason string) {
+	m.retries.WithLabelValues(rel, reason).Inc()
+}
+
+func statusClass(resp *http.Response, err error) string {
+	if err != nil || resp == nil {
+		return "error"
+	}
+	return strconv.Itoa(resp.StatusCode/100) + "xx"
+}
+
+// retryReason labels why a call is being retried: a connection/IO error, or which status
+// triggered it.
+func retryReason(resp *http.Response, err error) string {
+	if err != nil {
+		return "error"
+	}
+	return strconv.Itoa(resp.StatusCode)
+}
@@ -0,0 +1,67 @@
+/*
+Copyright (C) 2018 Expedia Group.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_WithMetrics_ShouldInstrumentRequestsAndRetries(t *testing.T) {
+	// given flyte-api's health check fails once with 503 before succeeding
+	var healthChecks int
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/info/health" {
+			healthChecks++
+			if healthChecks == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		fmt.Fprint(w, linksResponse("http://"+r.Host, "info/health"))
+	}
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	reg := prometheus.NewRegistry()
+	baseUrl, _ := url.Parse(server.URL)
+	c := NewClientWithOptions(baseUrl, 10*time.Second, WithMetrics(reg), WithRetryPolicy(fastRetryPolicy()))
+
+	// when
+	_, err := c.Request().Rel("info/health").Do(context.Background())
+	require.NoError(t, err)
+
+	// then the 503 was counted as a retry attributed to the rel that triggered it...
+	m := c.(*client).metrics
+	require.Equal(t, float64(1), testutil.ToFloat64(m.retries.WithLabelValues("info/health", "503")))
+	// ...the link refresh and the (now successful) health check were each counted once...
+	require.Equal(t, float64(1), testutil.ToFloat64(m.requests.WithLabelValues(getFlyteApiLinksRel, http.MethodGet, "2xx")))
+	require.Equal(t, float64(1), testutil.ToFloat64(m.requests.WithLabelValues("info/health", http.MethodGet, "2xx")))
+	// ...and no call is left counted as still in flight
+	require.Equal(t, float64(0), testutil.ToFloat64(m.inflight))
+}
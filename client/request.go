@@ -0,0 +1,200 @@
+/*
+Copyright (C) 2018 Expedia Group.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// relContextKey carries the HAL rel a request was built for through to the http.RoundTripper
+// layer, so its metrics can attribute a call without parsing the URL.
+type relContextKey struct{}
+
+func withRel(ctx context.Context, rel string) context.Context {
+	return context.WithValue(ctx, relContextKey{}, rel)
+}
+
+// relFromContext returns the rel a request was built for, or "" if its context was never given
+// one via withRel.
+func relFromContext(ctx context.Context) string {
+	rel, _ := ctx.Value(relContextKey{}).(string)
+	return rel
+}
+
+// Request is a fluent builder for calling any endpoint flyte-api advertises via a HAL link,
+// without the client needing a dedicated method for it. Build one with Client.Request(), e.g.:
+//
+//	var packs []Pack
+//	resp, err := c.Request().Rel("pack/listPacks").Query("name", "foo").Into(&packs).Do(ctx)
+type Request struct {
+	c          *client
+	rel        string
+	pathParams map[string]string
+	query      url.Values
+	header     http.Header
+	body       interface{}
+	into       interface{}
+}
+
+// Request returns a new Request builder targeting this client's flyte-api.
+func (c *client) Request() *Request {
+	return &Request{c: c, query: url.Values{}, header: http.Header{}}
+}
+
+// Rel sets the HAL rel to follow, e.g. "pack/listPacks" or "flow/listFlows". The href is resolved
+// against the client's cached flyte-api links, refreshing them once if the rel isn't found.
+func (r *Request) Rel(rel string) *Request {
+	r.rel = rel
+	return r
+}
+
+// PathParam substitutes "{k}" in the resolved href's path with an URL-escaped v.
+func (r *Request) PathParam(k, v string) *Request {
+	if r.pathParams == nil {
+		r.pathParams = map[string]string{}
+	}
+	r.pathParams[k] = v
+	return r
+}
+
+// Query adds a query parameter, URL-encoded when the request is built.
+func (r *Request) Query(k, v string) *Request {
+	r.query.Add(k, v)
+	return r
+}
+
+// Header adds a request header.
+func (r *Request) Header(k, v string) *Request {
+	r.header.Add(k, v)
+	return r
+}
+
+// Body sets v to be marshalled as the JSON request body; setting a body makes the request a POST.
+func (r *Request) Body(v interface{}) *Request {
+	r.body = v
+	return r
+}
+
+// Into decodes the JSON response body into out when Do succeeds.
+func (r *Request) Into(out interface{}) *Request {
+	r.into = out
+	return r
+}
+
+// Do resolves rel, applies any path params/query/headers/body, and performs the request through
+// the same retry/logging/metrics middleware used by the client's other methods. It returns
+// NotFoundError if flyte-api responds 404, consistent with TakeAction.
+func (r *Request) Do(ctx context.Context) (*http.Response, error) {
+	href, err := r.c.resolveHref(r.rel)
+	if err != nil {
+		return nil, err
+	}
+	u, err := url.Parse(href)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range r.pathParams {
+		u.Path = strings.Replace(u.Path, "{"+k+"}", url.PathEscape(v), -1)
+	}
+	if len(r.query) > 0 {
+		q := u.Query()
+		for k, vs := range r.query {
+			for _, v := range vs {
+				q.Add(k, v)
+			}
+		}
+		u.RawQuery = q.Encode()
+	}
+
+	method := http.MethodGet
+	var bodyReader *bytes.Reader
+	if r.body != nil {
+		method = http.MethodPost
+		b, err := json.Marshal(r.body)
+		if err != nil {
+			return nil, err
+		}
+		bodyReader = bytes.NewReader(b)
+	}
+
+	var req *http.Request
+	if bodyReader != nil {
+		req, err = http.NewRequest(method, u.String(), bodyReader)
+	} else {
+		req, err = http.NewRequest(method, u.String(), nil)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if r.body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for k, vs := range r.header {
+		for _, v := range vs {
+			req.Header.Add(k, v)
+		}
+	}
+	req = req.WithContext(withRel(ctx, r.rel))
+
+	resp, err := r.c.httpClient.Do(req)
+	if err != nil {
+		return nil, unwrapUnexpectedStatus(err)
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return resp, NotFoundError{url: u.String()}
+	}
+
+	if r.into != nil {
+		defer resp.Body.Close()
+		if err := json.NewDecoder(resp.Body).Decode(r.into); err != nil {
+			return resp, err
+		}
+	}
+
+	return resp, nil
+}
+
+// resolveHref looks up rel in the client's cached links, refreshing them once from flyte-api if
+// rel isn't found - new links added to flyte-api shouldn't require a new client release.
+func (c *client) resolveHref(rel string) (string, error) {
+	c.linksMu.Lock()
+	links := c.links
+	c.linksMu.Unlock()
+
+	if href, err := findHref(rel, links); err == nil {
+		return href, nil
+	}
+
+	refreshed, err := c.getFlyteApiLinks()
+	if err != nil {
+		_, findErr := findHref(rel, links)
+		return "", findErr
+	}
+
+	c.linksMu.Lock()
+	c.links = refreshed
+	c.linksMu.Unlock()
+
+	return findHref(rel, refreshed)
+}
@@ -0,0 +1,180 @@
+/*
+Copyright (C) 2018 Expedia Group.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testPack struct {
+	Name string `json:"name"`
+}
+
+// linksResponse builds a flyteApiLinksResponse-shaped document whose hrefs point back at server,
+// so Request.Do's HTTP calls land on the test server rather than the fixture's example.com hrefs.
+func linksResponse(server string, rels ...string) string {
+	links := fmt.Sprintf(`{"href": %q, "rel": "self"}`, server)
+	for _, rel := range rels {
+		links += fmt.Sprintf(`, {"href": %q, "rel": %q}`, server+"/"+rel, "http://example.com/swagger#!/"+rel)
+	}
+	return `{"links": [` + links + `]}`
+}
+
+func Test_Request_ShouldFollowRelAndDecodeResponse(t *testing.T) {
+	// given a flyte-api serving its root links, and listPacks filtering on the "name" query param
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/pack/listPacks":
+			assert.Equal(t, "foo", r.URL.Query().Get("name"))
+			w.Write([]byte(`[{"name":"foo"}]`))
+		default:
+			fmt.Fprint(w, linksResponse("http://"+r.Host, "pack/listPacks"))
+		}
+	}
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	baseUrl, _ := url.Parse(server.URL)
+	c := NewClient(baseUrl, 10*time.Second)
+
+	// when
+	var packs []testPack
+	resp, err := c.Request().Rel("pack/listPacks").Query("name", "foo").Into(&packs).Do(context.Background())
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Len(t, packs, 1)
+	assert.Equal(t, "foo", packs[0].Name)
+}
+
+func Test_Request_ShouldRefreshLinksOnMiss(t *testing.T) {
+	// given a flyte-api whose root links don't yet advertise findFlows when the client starts...
+	var linksRequests int
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/audit/findFlows":
+			w.Write([]byte(`[]`))
+		default:
+			linksRequests++
+			if linksRequests == 1 {
+				fmt.Fprint(w, `{"links": []}`)
+				return
+			}
+			fmt.Fprint(w, linksResponse("http://"+r.Host, "audit/findFlows"))
+		}
+	}
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	baseUrl, _ := url.Parse(server.URL)
+	c := NewClient(baseUrl, 10*time.Second)
+
+	// when: the client's cached links don't have audit/findFlows, so Do must refresh them
+	var flows []interface{}
+	_, err := c.Request().Rel("audit/findFlows").Into(&flows).Do(context.Background())
+
+	// then
+	require.NoError(t, err)
+}
+
+func Test_Request_ShouldSubstitutePathParamInHref(t *testing.T) {
+	// given a flyte-api advertising a templated href for pack/getPack
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/pack/getPack/foo":
+			w.Write([]byte(`{"name":"foo"}`))
+		default:
+			fmt.Fprint(w, linksResponse("http://"+r.Host, "pack/getPack/{name}"))
+		}
+	}
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	baseUrl, _ := url.Parse(server.URL)
+	c := NewClient(baseUrl, 10*time.Second)
+
+	// when
+	var pack testPack
+	resp, err := c.Request().Rel("pack/getPack/{name}").PathParam("name", "foo").Into(&pack).Do(context.Background())
+
+	// then the {name} placeholder was substituted into the request path
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "foo", pack.Name)
+}
+
+func Test_Request_ShouldPostBodyAsJSON(t *testing.T) {
+	// given a flyte-api that records what it received on pack/postEvent
+	var gotContentType string
+	var gotBody testPack
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/pack/postEvent":
+			gotContentType = r.Header.Get("Content-Type")
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&gotBody))
+			assert.Equal(t, http.MethodPost, r.Method)
+		default:
+			fmt.Fprint(w, linksResponse("http://"+r.Host, "pack/postEvent"))
+		}
+	}
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	baseUrl, _ := url.Parse(server.URL)
+	c := NewClient(baseUrl, 10*time.Second)
+
+	// when
+	_, err := c.Request().Rel("pack/postEvent").Body(testPack{Name: "foo"}).Do(context.Background())
+
+	// then the body was POSTed as JSON
+	require.NoError(t, err)
+	assert.Equal(t, "application/json", gotContentType)
+	assert.Equal(t, "foo", gotBody.Name)
+}
+
+func Test_Request_ShouldReturnNotFoundErrorWhenRelResolvesTo404(t *testing.T) {
+	// given
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/info/health" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, linksResponse("http://"+r.Host, "info/health"))
+	}
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	baseUrl, _ := url.Parse(server.URL)
+	c := NewClient(baseUrl, 10*time.Second)
+
+	// when
+	_, err := c.Request().Rel("info/health").Do(context.Background())
+
+	// then
+	assert.IsType(t, NotFoundError{}, err)
+}
@@ -0,0 +1,201 @@
+/*
+Copyright (C) 2018 Expedia Group.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how the client retries a flyte-api call that failed with a connection/IO
+// error, a 5xx response or a 429. Backoff is exponential with full jitter:
+// sleep = rand(0, min(MaxDelay, BaseDelay*2^attempt)). Retrying stops once MaxRetries retries
+// (MaxRetries+1 attempts in all) have been made, or once the next backoff would push the call past
+// MaxElapsedTime.
+type RetryPolicy struct {
+	MaxRetries     int
+	BaseDelay      time.Duration
+	MaxDelay       time.Duration
+	MaxElapsedTime time.Duration
+}
+
+// DefaultRetryPolicy is used when a client is created without an explicit WithRetryPolicy option:
+// 3 retries, backing off up to 30s between attempts, within a 30s overall budget.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxRetries:     3,
+		BaseDelay:      500 * time.Millisecond,
+		MaxDelay:       30 * time.Second,
+		MaxElapsedTime: 30 * time.Second,
+	}
+}
+
+// backoff returns a random delay in [0, min(MaxDelay, BaseDelay*2^attempt)].
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	capped := math.Min(float64(p.MaxDelay), float64(p.BaseDelay)*math.Pow(2, float64(attempt)))
+	if capped <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(capped) + 1))
+}
+
+// shouldRetry decides whether the call that produced (resp, err) on the given attempt (0-indexed)
+// should be retried, and if so after how long. A 429 honours Retry-After when present; any other
+// 4xx is never retried, so e.g. a 404 is returned to the caller immediately.
+func (p *RetryPolicy) shouldRetry(resp *http.Response, err error, attempt int) (bool, time.Duration) {
+	if attempt >= p.MaxRetries {
+		return false, 0
+	}
+	if err != nil {
+		return true, p.backoff(attempt)
+	}
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		if d, ok := retryAfter(resp.Header.Get("Retry-After")); ok {
+			return true, d
+		}
+		return true, p.backoff(attempt)
+	case resp.StatusCode >= 500:
+		return true, p.backoff(attempt)
+	default:
+		return false, 0
+	}
+}
+
+// retryAfter parses a Retry-After header in either the delta-seconds or HTTP-date form.
+func retryAfter(header string) (time.Duration, bool) {
+	if header == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(header); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(header); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// retryTransport is an http.RoundTripper that applies a RetryPolicy uniformly to every request
+// the client makes, so TakeAction, API link refreshes and Request() calls all get the same
+// retry-on-transient-failure behaviour.
+type retryTransport struct {
+	next    http.RoundTripper
+	policy  *RetryPolicy
+	logger  Logger
+	metrics *metrics
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		body, _ = ioutil.ReadAll(req.Body)
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+
+	rel := relFromContext(req.Context())
+	if t.metrics != nil {
+		t.metrics.inflight.Inc()
+		defer t.metrics.inflight.Dec()
+	}
+
+	ctx := req.Context()
+	start := time.Now()
+	for attempt := 0; ; attempt++ {
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("request to %s: %w", req.URL, err)
+		}
+
+		resp, err := t.next.RoundTrip(req)
+		if err != nil && ctx.Err() != nil {
+			return nil, fmt.Errorf("request to %s: %w", req.URL, ctx.Err())
+		}
+
+		retry, delay := t.policy.shouldRetry(resp, err, attempt)
+		if !retry {
+			if t.metrics != nil {
+				t.metrics.observe(rel, req.Method, resp, err, time.Since(start))
+			}
+			if err == nil {
+				if uerr := unexpectedStatusErr(req.URL.String(), resp); uerr != nil {
+					resp.Body.Close()
+					return nil, uerr
+				}
+			}
+			return resp, err
+		}
+		if resp != nil {
+			resp.Body.Close()
+		}
+		if time.Since(start)+delay > t.policy.MaxElapsedTime {
+			if t.metrics != nil {
+				t.metrics.observe(rel, req.Method, resp, err, time.Since(start))
+			}
+			if err == nil {
+				err = unexpectedStatusErr(req.URL.String(), resp)
+			}
+			return nil, err
+		}
+		if t.metrics != nil {
+			t.metrics.recordRetry(rel, retryReason(resp, err))
+		}
+
+		t.logger.Error("retrying request", "url", req.URL.String(), "method", req.Method, "attempt", attempt+1, "status", statusOf(resp), "err", err)
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return nil, fmt.Errorf("request to %s: %w", req.URL, ctx.Err())
+		}
+
+		if req.Body != nil {
+			req.Body = ioutil.NopCloser(bytes.NewReader(body))
+		}
+	}
+}
+
+func statusOf(resp *http.Response) string {
+	if resp == nil {
+		return "-"
+	}
+	return resp.Status
+}
+
+// unexpectedStatusErr returns an UnexpectedStatusError if resp's retry budget ran out while it was
+// still a retryable status (5xx, or 429); nil for a genuine success or a terminal status such as
+// 404, which callers handle themselves (e.g. NotFoundError).
+func unexpectedStatusErr(url string, resp *http.Response) error {
+	if resp == nil {
+		return nil
+	}
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+		return UnexpectedStatusError{url: url, status: resp.Status}
+	}
+	return nil
+}
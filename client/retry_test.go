@@ -0,0 +1,89 @@
+/*
+Copyright (C) 2018 Expedia Group.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func fastRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{MaxRetries: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond, MaxElapsedTime: time.Second}
+}
+
+func Test_TakeAction_ShouldRetryOnServiceUnavailableThenSucceed(t *testing.T) {
+	// given the mock flyte-api fails twice with 503 before returning an action
+	var requests int32
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"name":"doIt"}`))
+	}
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	u, _ := url.Parse(server.URL)
+	c := &client{takeActionURL: u, httpClient: &http.Client{
+		Transport: &retryTransport{next: http.DefaultTransport, policy: fastRetryPolicy(), logger: goLogger{}},
+	}}
+
+	// when
+	action, err := c.TakeAction()
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, "doIt", action.Name)
+	assert.EqualValues(t, 3, atomic.LoadInt32(&requests))
+}
+
+func Test_TakeAction_ShouldSurfaceErrorAfterRetriesExhausted(t *testing.T) {
+	// given the mock flyte-api always fails, with a realistic error body rather than an empty one -
+	// so this test can't pass by accident via a generic JSON-decode error
+	var requests int32
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(`{"error":"upstream unavailable"}`))
+	}
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	u, _ := url.Parse(server.URL)
+	policy := fastRetryPolicy()
+	c := &client{takeActionURL: u, httpClient: &http.Client{
+		Transport: &retryTransport{next: http.DefaultTransport, policy: policy, logger: goLogger{}},
+	}}
+
+	// when
+	action, err := c.TakeAction()
+
+	// then the call failed after exhausting the retry budget, having tried MaxRetries+1 times
+	assert.Nil(t, action)
+	if assert.Error(t, err) {
+		assert.IsType(t, UnexpectedStatusError{}, err)
+	}
+	assert.EqualValues(t, policy.MaxRetries+1, atomic.LoadInt32(&requests))
+}